@@ -0,0 +1,89 @@
+package gpio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// AnalogPin represents a single ADC channel exposed through the Linux IIO subsystem,
+// e.g. the on-board ADCs found on BeagleBone-style boards.
+type AnalogPin struct {
+	Device  uint
+	Channel uint
+	scale   float64
+}
+
+// NewAnalogInput opens channel on the IIO device iio:deviceN for reading.
+func NewAnalogInput(device, channel uint) (AnalogPin, error) {
+	pin := AnalogPin{Device: device, Channel: channel}
+
+	scale, err := readIIOFloat(device, fmt.Sprintf("in_voltage%d_scale", channel))
+	if err != nil {
+		return pin, fmt.Errorf("failed to read scale for iio:device%d channel %d: %v", device, channel, err)
+	}
+	pin.scale = scale
+
+	return pin, nil
+}
+
+// NewAnalogInputByName opens the analog pin known as name on the active board
+// Descriptor. SetBoard or DetectBoard must have been called beforehand.
+func NewAnalogInputByName(name string) (AnalogPin, error) {
+	if activeBoard == nil {
+		return AnalogPin{}, errors.New("no board descriptor configured, call SetBoard or DetectBoard first")
+	}
+	return activeBoard.AnalogPinByName(name)
+}
+
+func iioPath(device uint, attr string) string {
+	return fmt.Sprintf("/sys/bus/iio/devices/iio:device%d/%s", device, attr)
+}
+
+func readIIOFloat(device uint, attr string) (float64, error) {
+	raw, err := os.ReadFile(iioPath(device, attr))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(raw)), 64)
+}
+
+// Read returns the raw ADC count for the channel.
+func (a AnalogPin) Read() (int, error) {
+	raw, err := os.ReadFile(iioPath(a.Device, fmt.Sprintf("in_voltage%d_raw", a.Channel)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read iio:device%d channel %d: %v", a.Device, a.Channel, err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(raw)))
+}
+
+// ReadVoltage returns the channel reading in volts, applying the channel's scale (in
+// millivolts per count, as published by the IIO driver).
+func (a AnalogPin) ReadVoltage() (float64, error) {
+	raw, err := a.Read()
+	if err != nil {
+		return 0, err
+	}
+	return float64(raw) * a.scale / 1000, nil
+}
+
+// defaultADCResolution is used by Resolution for IIO devices this package does not have
+// a known bit width for.
+const defaultADCResolution = 12
+
+// adcResolutions maps known IIO device numbers to their fixed ADC bit width, a property
+// of the hardware rather than of any single sample. Add an entry here when wiring up a
+// new board in board.go.
+var adcResolutions = map[uint]uint{
+	0: 12, // TI AM335x on-chip ADC (BeagleBone Black)
+}
+
+// Resolution reports the ADC bit width of the channel's device.
+func (a AnalogPin) Resolution() uint {
+	if bits, ok := adcResolutions[a.Device]; ok {
+		return bits
+	}
+	return defaultADCResolution
+}
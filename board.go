@@ -0,0 +1,246 @@
+package gpio
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Capability is a bitmask describing what a pin can be used for.
+type Capability uint
+
+const (
+	CapDigital Capability = 1 << iota
+	CapI2C
+	CapSPI
+	CapUART
+	CapAnalog
+	CapPWM
+)
+
+// PinInfo describes a single named pin exposed by a board Descriptor. Device and Channel
+// are only meaningful for pins with the CapAnalog capability, identifying the IIO device
+// and channel backing them.
+type PinInfo struct {
+	Name    string
+	Number  uint
+	Caps    Capability
+	Device  uint
+	Channel uint
+
+	// PWMChip and PWMChannel are only meaningful for pins with the CapPWM capability.
+	PWMChip    uint
+	PWMChannel uint
+}
+
+// Descriptor maps the symbolic pin names of a specific host to the pin
+// numbers known by the kernel, along with what each pin can be used for.
+type Descriptor interface {
+	// Name returns a human readable identifier for the board, e.g. "raspberry-pi-rev2"
+	Name() string
+	// PinByName resolves a symbolic pin name, e.g. "P1_11" or "GPIO_17", to a Pin
+	PinByName(name string) (Pin, error)
+	// AnalogPinByName resolves a symbolic pin name to an AnalogPin, for names with the
+	// CapAnalog capability
+	AnalogPinByName(name string) (AnalogPin, error)
+	// PWMPinByName resolves a symbolic pin name to a PWMPin, for names with the CapPWM
+	// capability
+	PWMPinByName(name string) (PWMPin, error)
+	// Pins returns every named pin known to this descriptor
+	Pins() []PinInfo
+}
+
+// pinMap is a Descriptor backed by a simple name -> PinInfo table, which covers
+// every host currently supported by this package.
+type pinMap struct {
+	name string
+	pins map[string]PinInfo
+}
+
+func newPinMap(name string, pins []PinInfo) *pinMap {
+	m := &pinMap{name: name, pins: make(map[string]PinInfo, len(pins))}
+	for _, p := range pins {
+		m.pins[p.Name] = p
+	}
+	return m
+}
+
+func (m *pinMap) Name() string {
+	return m.name
+}
+
+func (m *pinMap) Pins() []PinInfo {
+	out := make([]PinInfo, 0, len(m.pins))
+	for _, p := range m.pins {
+		out = append(out, p)
+	}
+	return out
+}
+
+func (m *pinMap) PinByName(name string) (Pin, error) {
+	info, ok := m.pins[name]
+	if !ok {
+		return Pin{}, fmt.Errorf("unknown pin name %q on board %q", name, m.name)
+	}
+	if info.Caps&CapDigital == 0 {
+		return Pin{}, fmt.Errorf("pin %q on board %q is not a digital pin", name, m.name)
+	}
+	return Pin{Number: info.Number}, nil
+}
+
+func (m *pinMap) AnalogPinByName(name string) (AnalogPin, error) {
+	info, ok := m.pins[name]
+	if !ok {
+		return AnalogPin{}, fmt.Errorf("unknown pin name %q on board %q", name, m.name)
+	}
+	if info.Caps&CapAnalog == 0 {
+		return AnalogPin{}, fmt.Errorf("pin %q on board %q is not an analog pin", name, m.name)
+	}
+	return NewAnalogInput(info.Device, info.Channel)
+}
+
+func (m *pinMap) PWMPinByName(name string) (PWMPin, error) {
+	info, ok := m.pins[name]
+	if !ok {
+		return PWMPin{}, fmt.Errorf("unknown pin name %q on board %q", name, m.name)
+	}
+	if info.Caps&CapPWM == 0 {
+		return PWMPin{}, fmt.Errorf("pin %q on board %q is not a PWM pin", name, m.name)
+	}
+	return NewPWM(info.PWMChip, info.PWMChannel)
+}
+
+// raspberryPiRev1Descriptor covers the original 26 pin P1 header
+var raspberryPiRev1Descriptor = newPinMap("raspberry-pi-rev1", []PinInfo{
+	{Name: "P1_03", Number: 0, Caps: CapDigital | CapI2C},
+	{Name: "P1_05", Number: 1, Caps: CapDigital | CapI2C},
+	{Name: "P1_07", Number: 4, Caps: CapDigital},
+	{Name: "P1_11", Number: 17, Caps: CapDigital},
+	{Name: "P1_12", Number: 18, Caps: CapDigital | CapPWM, PWMChip: 0, PWMChannel: 0},
+	{Name: "P1_13", Number: 21, Caps: CapDigital},
+	{Name: "P1_15", Number: 22, Caps: CapDigital},
+	{Name: "P1_19", Number: 10, Caps: CapDigital | CapSPI},
+	{Name: "P1_21", Number: 9, Caps: CapDigital | CapSPI},
+	{Name: "P1_23", Number: 11, Caps: CapDigital | CapSPI},
+})
+
+// raspberryPiRev2Descriptor covers the rev2 P1 header, where three pins were reassigned
+var raspberryPiRev2Descriptor = newPinMap("raspberry-pi-rev2", []PinInfo{
+	{Name: "P1_03", Number: 2, Caps: CapDigital | CapI2C},
+	{Name: "P1_05", Number: 3, Caps: CapDigital | CapI2C},
+	{Name: "P1_07", Number: 4, Caps: CapDigital},
+	{Name: "P1_11", Number: 17, Caps: CapDigital},
+	{Name: "P1_12", Number: 18, Caps: CapDigital | CapPWM, PWMChip: 0, PWMChannel: 0},
+	{Name: "P1_13", Number: 27, Caps: CapDigital},
+	{Name: "P1_15", Number: 22, Caps: CapDigital},
+	{Name: "P1_19", Number: 10, Caps: CapDigital | CapSPI},
+	{Name: "P1_21", Number: 9, Caps: CapDigital | CapSPI},
+	{Name: "P1_23", Number: 11, Caps: CapDigital | CapSPI},
+})
+
+// bcm2711Descriptor covers the 40 pin header of BCM2711 based boards such as the Pi 4
+var bcm2711Descriptor = newPinMap("bcm2711", []PinInfo{
+	{Name: "GPIO_2", Number: 2, Caps: CapDigital | CapI2C},
+	{Name: "GPIO_3", Number: 3, Caps: CapDigital | CapI2C},
+	{Name: "GPIO_4", Number: 4, Caps: CapDigital},
+	{Name: "GPIO_14", Number: 14, Caps: CapDigital | CapUART},
+	{Name: "GPIO_15", Number: 15, Caps: CapDigital | CapUART},
+	{Name: "GPIO_17", Number: 17, Caps: CapDigital},
+	{Name: "GPIO_18", Number: 18, Caps: CapDigital | CapPWM, PWMChip: 0, PWMChannel: 0},
+	{Name: "SPI0_MOSI", Number: 10, Caps: CapDigital | CapSPI},
+	{Name: "SPI0_MISO", Number: 9, Caps: CapDigital | CapSPI},
+	{Name: "SPI0_SCLK", Number: 11, Caps: CapDigital | CapSPI},
+})
+
+// beagleboneBlackDescriptor covers the P8/P9 header pins commonly used for GPIO and ADC
+var beagleboneBlackDescriptor = newPinMap("beaglebone-black", []PinInfo{
+	{Name: "P8_3", Number: 38, Caps: CapDigital},
+	{Name: "P8_4", Number: 39, Caps: CapDigital},
+	{Name: "P8_7", Number: 66, Caps: CapDigital},
+	{Name: "P9_11", Number: 30, Caps: CapDigital | CapUART},
+	{Name: "P9_12", Number: 60, Caps: CapDigital},
+	{Name: "P9_14", Number: 50, Caps: CapDigital | CapPWM, PWMChip: 2, PWMChannel: 0},
+	{Name: "P9_17", Number: 5, Caps: CapDigital | CapI2C},
+	{Name: "P9_18", Number: 4, Caps: CapDigital | CapI2C},
+	{Name: "P9_33", Caps: CapAnalog, Device: 0, Channel: 4},
+	{Name: "P9_39", Caps: CapAnalog, Device: 0, Channel: 0},
+})
+
+// genericSysfsDescriptor resolves a name of the form "GPIO_n" directly to kernel pin n, for
+// hosts this package does not recognize but that still expose the sysfs gpio interface.
+type genericSysfsDescriptor struct{}
+
+func (genericSysfsDescriptor) Name() string {
+	return "generic-sysfs"
+}
+
+func (genericSysfsDescriptor) Pins() []PinInfo {
+	return nil
+}
+
+func (genericSysfsDescriptor) PinByName(name string) (Pin, error) {
+	var n uint
+	if _, err := fmt.Sscanf(name, "GPIO_%d", &n); err != nil {
+		return Pin{}, fmt.Errorf("generic-sysfs board cannot resolve pin name %q", name)
+	}
+	return Pin{Number: n}, nil
+}
+
+func (genericSysfsDescriptor) AnalogPinByName(name string) (AnalogPin, error) {
+	return AnalogPin{}, fmt.Errorf("generic-sysfs board does not support analog pins, requested %q", name)
+}
+
+func (genericSysfsDescriptor) PWMPinByName(name string) (PWMPin, error) {
+	return PWMPin{}, fmt.Errorf("generic-sysfs board does not support PWM pins, requested %q", name)
+}
+
+var genericSysfs Descriptor = genericSysfsDescriptor{}
+
+// activeBoard is the Descriptor used by NewInputByName and NewOutputByName. It is nil
+// until SetBoard or DetectBoard is called.
+var activeBoard Descriptor
+
+// SetBoard sets the Descriptor used to resolve symbolic pin names.
+func SetBoard(d Descriptor) {
+	activeBoard = d
+}
+
+// Board returns the currently active Descriptor, or nil if none has been set.
+func Board() Descriptor {
+	return activeBoard
+}
+
+// DetectBoard identifies the host this process is running on by inspecting
+// /proc/cpuinfo and the device tree "compatible" property, sets it as the
+// active board and returns its Descriptor.
+func DetectBoard() (Descriptor, error) {
+	compatible, err := os.ReadFile("/proc/device-tree/compatible")
+	if err != nil {
+		compatible = nil
+	}
+
+	cpuinfo, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read /proc/cpuinfo: %v", err)
+	}
+
+	var d Descriptor
+	switch {
+	case bytes.Contains(compatible, []byte("raspberrypi,4-model-b")),
+		bytes.Contains(compatible, []byte("brcm,bcm2711")):
+		d = bcm2711Descriptor
+	case bytes.Contains(compatible, []byte("ti,am335x-bone-black")):
+		d = beagleboneBlackDescriptor
+	case strings.Contains(string(cpuinfo), "Revision\t: 0002"),
+		strings.Contains(string(cpuinfo), "Revision\t: 0003"):
+		d = raspberryPiRev1Descriptor
+	case strings.Contains(string(cpuinfo), "BCM2708"), strings.Contains(string(cpuinfo), "BCM2835"):
+		d = raspberryPiRev2Descriptor
+	default:
+		d = genericSysfs
+	}
+
+	SetBoard(d)
+	return d, nil
+}
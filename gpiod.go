@@ -0,0 +1,304 @@
+package gpio
+
+import (
+	"fmt"
+	"os"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Backend is implemented by every way this package knows how to talk to GPIO
+// lines on the host kernel.
+type Backend interface {
+	// Name identifies the backend, e.g. "sysfs" or "gpiod"
+	Name() string
+	// Available reports whether this backend can be used on the running kernel
+	Available() bool
+}
+
+type sysfsBackend struct{}
+
+func (sysfsBackend) Name() string { return "sysfs" }
+
+func (sysfsBackend) Available() bool {
+	_, err := os.Stat("/sys/class/gpio/export")
+	return err == nil
+}
+
+type gpiodBackend struct{}
+
+func (gpiodBackend) Name() string { return "gpiod" }
+
+func (gpiodBackend) Available() bool {
+	_, err := os.Stat("/dev/gpiochip0")
+	return err == nil
+}
+
+// defaultBackend auto-selects the chardev backend only where sysfs is unavailable.
+// Raw kernel GPIO numbers, as used by NewInput/NewOutput, are sysfs's numbering space,
+// not a gpiochip0 line offset, so preferring chardev whenever it merely exists would
+// silently reinterpret those numbers on any host exposing both interfaces.
+func defaultBackend() Backend {
+	if (sysfsBackend{}).Available() {
+		return sysfsBackend{}
+	}
+	return gpiodBackend{}
+}
+
+// Bias configures the internal pull resistor of a requested line.
+type Bias uint
+
+const (
+	BiasDefault Bias = iota
+	BiasDisable
+	BiasPullUp
+	BiasPullDown
+)
+
+// Drive configures how a requested output line drives its signal.
+type Drive uint
+
+const (
+	DrivePushPull Drive = iota
+	DriveOpenDrain
+	DriveOpenSource
+)
+
+// LineConfig describes how a line should be requested from a Chip. These options are
+// only reachable through the GPIO character device ioctl interface, not sysfs.
+type LineConfig struct {
+	Direction    direction
+	InitialValue uint
+	Bias         Bias
+	Drive        Drive
+	ActiveLow    bool
+	Debounce     time.Duration
+}
+
+const gpioV2LinesMax = 64
+
+// gpioV2LineValues mirrors struct gpio_v2_line_values from <linux/gpio.h>
+type gpioV2LineValues struct {
+	Bits uint64
+	Mask uint64
+}
+
+// gpioV2LineAttribute mirrors struct gpio_v2_line_attribute
+type gpioV2LineAttribute struct {
+	ID      uint32
+	_       uint32
+	Padding [8]byte // union of Flags/Values/Debounce, sized for the largest member
+}
+
+// gpioV2LineConfigAttribute mirrors struct gpio_v2_line_config_attribute
+type gpioV2LineConfigAttribute struct {
+	Attr gpioV2LineAttribute
+	Mask uint64
+}
+
+// gpioV2LineConfig mirrors struct gpio_v2_line_config
+type gpioV2LineConfig struct {
+	Flags    uint64
+	NumAttrs uint32
+	Padding  [5]uint32
+	Attrs    [10]gpioV2LineConfigAttribute
+}
+
+// gpioV2LineRequest mirrors struct gpio_v2_line_request
+type gpioV2LineRequest struct {
+	Offsets      [gpioV2LinesMax]uint32
+	Consumer     [32]byte
+	Config       gpioV2LineConfig
+	NumLines     uint32
+	EventBufSize uint32
+	Padding      [5]uint32
+	Fd           int32
+}
+
+const (
+	gpioV2LineFlagActiveLow   = 1 << 1
+	gpioV2LineFlagInput       = 1 << 2
+	gpioV2LineFlagOutput      = 1 << 3
+	gpioV2LineFlagEdgeRising  = 1 << 4
+	gpioV2LineFlagEdgeFalling = 1 << 5
+	gpioV2LineFlagOpenDrain   = 1 << 6
+	gpioV2LineFlagOpenSource  = 1 << 7
+	gpioV2LineFlagBiasPullUp  = 1 << 8
+	gpioV2LineFlagBiasPullDn  = 1 << 9
+	gpioV2LineFlagBiasDisable = 1 << 10
+)
+
+const gpioV2LineAttrIDDebounce = 3
+
+// gpioV2LineEvent mirrors struct gpio_v2_line_event from <linux/gpio.h>, as delivered by
+// a read() of a requested line's fd once edge detection is enabled on it.
+type gpioV2LineEvent struct {
+	Timestamp uint64
+	ID        uint32
+	Offset    uint32
+	Seqno     uint32
+	LineSeqno uint32
+	Padding   [6]uint32
+}
+
+const (
+	gpioV2LineEventRisingEdge  = 1
+	gpioV2LineEventFallingEdge = 2
+)
+
+func ioctlEncode(dir, typ, nr, size uintptr) uintptr {
+	const iocNone, iocWrite, iocRead = 0, 1, 2
+	_ = iocNone
+	_ = iocWrite
+	_ = iocRead
+	return (dir << 30) | (typ << 8) | nr | (size << 16)
+}
+
+var gpioV2GetLineIoctl = ioctlEncode(3, 0xB4, 0x07, unsafe.Sizeof(gpioV2LineRequest{}))
+var gpioV2LineSetConfigIoctl = ioctlEncode(3, 0xB4, 0x0D, unsafe.Sizeof(gpioV2LineConfig{}))
+var gpioV2LineSetValuesIoctl = ioctlEncode(3, 0xB4, 0x0F, unsafe.Sizeof(gpioV2LineValues{}))
+var gpioV2LineGetValuesIoctl = ioctlEncode(3, 0xB4, 0x0E, unsafe.Sizeof(gpioV2LineValues{}))
+
+// Chip represents an open /dev/gpiochipN character device.
+type Chip struct {
+	Name string
+	f    *os.File
+}
+
+// OpenChip opens the named gpiochip device, e.g. "gpiochip0".
+func OpenChip(name string) (*Chip, error) {
+	f, err := os.OpenFile(fmt.Sprintf("/dev/%s", name), os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", name, err)
+	}
+	return &Chip{Name: name, f: f}, nil
+}
+
+// Close releases the Chip's file descriptor. Lines already requested from it remain usable.
+func (c *Chip) Close() error {
+	return c.f.Close()
+}
+
+// RequestLine requests offset from the chip with the given configuration and returns a
+// Pin backed by the character device line file descriptor.
+func (c *Chip) RequestLine(offset uint, cfg LineConfig) (Pin, error) {
+	req := gpioV2LineRequest{
+		NumLines: 1,
+	}
+	req.Offsets[0] = uint32(offset)
+	copy(req.Consumer[:], "gpio")
+
+	var flags uint64
+	if cfg.Direction == inDirection {
+		flags |= gpioV2LineFlagInput
+	} else {
+		flags |= gpioV2LineFlagOutput
+	}
+	if cfg.ActiveLow {
+		flags |= gpioV2LineFlagActiveLow
+	}
+	switch cfg.Drive {
+	case DriveOpenDrain:
+		flags |= gpioV2LineFlagOpenDrain
+	case DriveOpenSource:
+		flags |= gpioV2LineFlagOpenSource
+	}
+	switch cfg.Bias {
+	case BiasPullUp:
+		flags |= gpioV2LineFlagBiasPullUp
+	case BiasPullDown:
+		flags |= gpioV2LineFlagBiasPullDn
+	case BiasDisable:
+		flags |= gpioV2LineFlagBiasDisable
+	}
+	req.Config.Flags = flags
+	if cfg.Debounce > 0 {
+		attr := &req.Config.Attrs[0]
+		attr.Attr.ID = gpioV2LineAttrIDDebounce
+		*(*uint32)(unsafe.Pointer(&attr.Attr.Padding[0])) = uint32(cfg.Debounce.Microseconds())
+		attr.Mask = 1
+		req.Config.NumAttrs = 1
+	}
+
+	if err := ioctl(c.f.Fd(), gpioV2GetLineIoctl, uintptr(unsafe.Pointer(&req))); err != nil {
+		return Pin{}, fmt.Errorf("failed to request line %d on %s: %v", offset, c.Name, err)
+	}
+
+	lineFile := os.NewFile(uintptr(req.Fd), fmt.Sprintf("%s/line%d", c.Name, offset))
+	pin := Pin{
+		Number:    offset,
+		direction: cfg.Direction,
+		f:         lineFile,
+		backend:   gpiodBackend{},
+	}
+
+	if cfg.Direction == outDirection && cfg.InitialValue != 0 {
+		if err := setLineValue(pin, cfg.InitialValue); err != nil {
+			return pin, err
+		}
+	}
+
+	return pin, nil
+}
+
+func setLineValue(p Pin, v uint) error {
+	values := gpioV2LineValues{Mask: 1}
+	if v != 0 {
+		values.Bits = 1
+	}
+	return ioctl(p.f.Fd(), gpioV2LineSetValuesIoctl, uintptr(unsafe.Pointer(&values)))
+}
+
+func getLineValue(p Pin) (uint, error) {
+	values := gpioV2LineValues{Mask: 1}
+	if err := ioctl(p.f.Fd(), gpioV2LineGetValuesIoctl, uintptr(unsafe.Pointer(&values))); err != nil {
+		return 0, err
+	}
+	return uint(values.Bits & 1), nil
+}
+
+// setLineEdgeDetection reconfigures an already-requested input line to report the given
+// edge as GPIO_V2_LINE_EVENT records on its fd.
+func setLineEdgeDetection(p Pin, edge Edge) error {
+	cfg := gpioV2LineConfig{Flags: gpioV2LineFlagInput}
+	switch edge {
+	case EdgeRising:
+		cfg.Flags |= gpioV2LineFlagEdgeRising
+	case EdgeFalling:
+		cfg.Flags |= gpioV2LineFlagEdgeFalling
+	case EdgeBoth:
+		cfg.Flags |= gpioV2LineFlagEdgeRising | gpioV2LineFlagEdgeFalling
+	}
+	return ioctl(p.f.Fd(), gpioV2LineSetConfigIoctl, uintptr(unsafe.Pointer(&cfg)))
+}
+
+// readLineEvent reads and decodes a single gpio_v2_line_event from a line fd that has
+// edge detection enabled via setLineEdgeDetection.
+func readLineEvent(p Pin) (Event, error) {
+	var raw [unsafe.Sizeof(gpioV2LineEvent{})]byte
+	n, err := p.f.Read(raw[:])
+	if err != nil {
+		return Event{}, err
+	}
+	if n != len(raw) {
+		return Event{}, fmt.Errorf("short read of gpio_v2_line_event for pin %d", p.Number)
+	}
+
+	ev := (*gpioV2LineEvent)(unsafe.Pointer(&raw[0]))
+	edge, val := EdgeRising, Active
+	if ev.ID == gpioV2LineEventFallingEdge {
+		edge, val = EdgeFalling, Inactive
+	}
+
+	return Event{Pin: p.Number, Value: val, Edge: edge, Timestamp: time.Unix(0, int64(ev.Timestamp))}, nil
+}
+
+func ioctl(fd uintptr, req uintptr, arg uintptr) error {
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, fd, req, arg)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -10,45 +10,84 @@ type Pin struct {
 	Number    uint
 	direction direction
 	f         *os.File
+	backend   Backend
 }
 
 // NewInput opens the given pin number for reading. The number provided should be the pin number known by the kernel
 func NewInput(p uint) (Pin, error) {
-	pin := Pin{
-		Number: p,
+	return newPin(p, inDirection, 0)
+}
+
+// NewOutput opens the given pin number for writing. The number provided should be the pin number known by the kernel
+// NewOutput also needs to know whether the pin should be initialized high (true) or low (false)
+func NewOutput(p uint, initHigh bool) (Pin, error) {
+	initVal := uint(0)
+	if initHigh {
+		initVal = uint(1)
 	}
-	err := exportGPIO(pin)
+	return newPin(p, outDirection, initVal)
+}
+
+// newPin opens pin number p for dir, auto-selecting between the chardev and sysfs
+// backends based on which one defaultBackend reports as available on this kernel.
+func newPin(p uint, dir direction, initialValue uint) (Pin, error) {
+	if _, ok := defaultBackend().(gpiodBackend); ok {
+		return newPinChardev(p, dir, initialValue)
+	}
+	return newPinSysfs(p, dir, initialValue)
+}
+
+// newPinChardev requests p as an offset on gpiochip0, the only chardev this package
+// requests raw pin numbers from.
+func newPinChardev(p uint, dir direction, initialValue uint) (Pin, error) {
+	chip, err := OpenChip("gpiochip0")
 	if err != nil {
+		return Pin{}, err
+	}
+	defer chip.Close()
+
+	return chip.RequestLine(p, LineConfig{Direction: dir, InitialValue: initialValue})
+}
+
+func newPinSysfs(p uint, dir direction, initialValue uint) (Pin, error) {
+	pin := Pin{
+		Number:  p,
+		backend: sysfsBackend{},
+	}
+	if err := exportGPIO(pin); err != nil {
 		return pin, err
 	}
-	pin.direction = inDirection
-	err = setDirection(pin, inDirection, 0)
-	if err != nil {
+	pin.direction = dir
+	if err := setDirection(pin, dir, initialValue); err != nil {
 		return pin, err
 	}
-	return openPin(pin, false)
+	return openPin(pin, dir == outDirection)
 }
 
-// NewOutput opens the given pin number for writing. The number provided should be the pin number known by the kernel
-// NewOutput also needs to know whether the pin should be initialized high (true) or low (false)
-func NewOutput(p uint, initHigh bool) (Pin, error) {
-	pin := Pin{
-		Number: p,
+// NewInputByName opens for reading the pin known as name on the active board Descriptor.
+// SetBoard or DetectBoard must have been called beforehand.
+func NewInputByName(name string) (Pin, error) {
+	if activeBoard == nil {
+		return Pin{}, errors.New("no board descriptor configured, call SetBoard or DetectBoard first")
 	}
-	err := exportGPIO(pin)
+	pin, err := activeBoard.PinByName(name)
 	if err != nil {
-		return pin, err
+		return Pin{}, err
 	}
-	initVal := uint(0)
-	if initHigh {
-		initVal = uint(1)
+	return NewInput(pin.Number)
+}
+
+// NewOutputByName opens for writing the pin known as name on the active board Descriptor,
+// initialized high (true) or low (false). SetBoard or DetectBoard must have been called beforehand.
+func NewOutputByName(name string, initHigh bool) (Pin, error) {
+	if activeBoard == nil {
+		return Pin{}, errors.New("no board descriptor configured, call SetBoard or DetectBoard first")
 	}
-	pin.direction = outDirection
-	err = setDirection(pin, outDirection, initVal)
+	pin, err := activeBoard.PinByName(name)
 	if err != nil {
-		return pin, err
+		return Pin{}, err
 	}
-	return openPin(pin, true)
+	return NewOutput(pin.Number, initHigh)
 }
 
 // Close releases the resources related to Pin. This doen't unexport Pin, use Cleanup() instead
@@ -59,20 +98,30 @@ func (p Pin) Close() {
 	}
 }
 
-// Cleanup close Pin and unexport it
+// Cleanup close Pin and unexport it. Pins obtained from a Chip have no sysfs export to
+// undo; their line is released back to the chip when Close is called.
 func (p Pin) Cleanup() error {
 	p.Close()
+	if _, ok := p.backend.(gpiodBackend); ok {
+		return nil
+	}
 	return unexportGPIO(p)
 }
 
 // Read returns the value read at the pin as reported by the kernel. This should only be used for input pins
 func (p Pin) Read() (uint, error) {
+	if _, ok := p.backend.(gpiodBackend); ok {
+		return getLineValue(p)
+	}
 	return readPin(p)
 }
 
 // SetLogicLevel sets the logic level for the Pin. This can be
 // either "active high" or "active low"
 func (p Pin) SetLogicLevel(logicLevel LogicLevel) error {
+	if _, ok := p.backend.(gpiodBackend); ok {
+		return errors.New("active_low must be set via LineConfig.ActiveLow when requesting a chardev line")
+	}
 	return setLogicLevel(p, logicLevel)
 }
 
@@ -81,6 +130,9 @@ func (p Pin) High() error {
 	if p.direction != outDirection {
 		return errors.New("pin is not configured for output")
 	}
+	if _, ok := p.backend.(gpiodBackend); ok {
+		return setLineValue(p, 1)
+	}
 	return writePin(p, 1)
 }
 
@@ -89,5 +141,8 @@ func (p Pin) Low() error {
 	if p.direction != outDirection {
 		return errors.New("pin is not configured for output")
 	}
+	if _, ok := p.backend.(gpiodBackend); ok {
+		return setLineValue(p, 0)
+	}
 	return writePin(p, 0)
 }
@@ -0,0 +1,56 @@
+package gpio
+
+import (
+	"fmt"
+	"time"
+)
+
+// PollEdge emulates edge-triggered notification for pins whose driver does not expose a
+// sysfs "edge" attribute, by reading the pin's value on every tick of interval and
+// comparing it against the previously observed value to detect rising/falling/both
+// transitions as requested. The returned cancel func stops the polling goroutine and
+// closes the event channel; it coexists with the interrupt-based Watch API so callers
+// can pick whichever mechanism suits their hardware.
+func PollEdge(pin Pin, edge Edge, interval time.Duration) (<-chan Event, func(), error) {
+	if pin.direction != inDirection {
+		return nil, nil, fmt.Errorf("pin %d is not configured for input", pin.Number)
+	}
+
+	initial, err := readPin(pin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	events := make(chan Event, 1)
+	stop := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		defer close(events)
+
+		last := Value(initial)
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				val, err := readPin(pin)
+				if err != nil {
+					continue
+				}
+				detected := edgeFor(last, Value(val))
+				last = Value(val)
+				if detected == EdgeNone || (edge != EdgeBoth && detected != edge) {
+					continue
+				}
+				select {
+				case events <- Event{Pin: pin.Number, Value: Value(val), Edge: detected, Timestamp: now}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return events, func() { close(stop) }, nil
+}
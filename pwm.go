@@ -0,0 +1,158 @@
+package gpio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Polarity selects the active level of a PWM output.
+type Polarity uint
+
+const (
+	PolarityNormal Polarity = iota
+	PolarityInversed
+)
+
+// PWMPin represents a single channel of a sysfs pwmchip.
+type PWMPin struct {
+	Chip    uint
+	Channel uint
+}
+
+// NewPWM exports channel on the given pwmchip and returns a PWMPin ready for
+// configuration. The export handshake uses the same waitUntilWritable pattern exportGPIO
+// uses for gpio pins.
+func NewPWM(chip, channel uint) (PWMPin, error) {
+	p := PWMPin{Chip: chip, Channel: channel}
+	if err := p.Export(); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// NewPWMByName exports the PWM pin known as name on the active board Descriptor.
+// SetBoard or DetectBoard must have been called beforehand.
+func NewPWMByName(name string) (PWMPin, error) {
+	if activeBoard == nil {
+		return PWMPin{}, errors.New("no board descriptor configured, call SetBoard or DetectBoard first")
+	}
+	return activeBoard.PWMPinByName(name)
+}
+
+func (p PWMPin) path(attr string) string {
+	return fmt.Sprintf("/sys/class/pwm/pwmchip%d/pwm%d/%s", p.Chip, p.Channel, attr)
+}
+
+// Export requests the channel be instantiated by the pwmchip driver.
+func (p PWMPin) Export() error {
+	export, err := os.OpenFile(fmt.Sprintf("/sys/class/pwm/pwmchip%d/export", p.Chip), os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open pwmchip%d export file for writing: %v", p.Chip, err)
+	}
+	defer export.Close()
+	if _, err := export.Write([]byte(strconv.Itoa(int(p.Channel)))); err != nil {
+		return fmt.Errorf("failed to export pwmchip%d channel %d: %v", p.Chip, p.Channel, err)
+	}
+
+	start := time.Now()
+	for {
+		if time.Since(start) >= timeout {
+			return fmt.Errorf("exporting pwmchip%d channel %d took more than %v", p.Chip, p.Channel, timeout)
+		}
+		if unix.Access(p.path("period"), unix.W_OK) == nil {
+			return nil
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Close unexports the channel, releasing it back to the pwmchip driver.
+func (p PWMPin) Close() error {
+	unexport, err := os.OpenFile(fmt.Sprintf("/sys/class/pwm/pwmchip%d/unexport", p.Chip), os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open pwmchip%d unexport file for writing: %v", p.Chip, err)
+	}
+	defer unexport.Close()
+	_, err = unexport.Write([]byte(strconv.Itoa(int(p.Channel))))
+	return err
+}
+
+func (p PWMPin) writeAttr(attr, value string) error {
+	f, err := os.OpenFile(p.path(attr), os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open pwmchip%d channel %d %s file for writing: %v", p.Chip, p.Channel, attr, err)
+	}
+	defer f.Close()
+	_, err = f.Write([]byte(value))
+	return err
+}
+
+// SetPeriod sets the PWM signal period.
+func (p PWMPin) SetPeriod(d time.Duration) error {
+	return p.writeAttr("period", strconv.FormatInt(d.Nanoseconds(), 10))
+}
+
+// SetDutyCycle sets how long within each period the signal stays active.
+func (p PWMPin) SetDutyCycle(d time.Duration) error {
+	return p.writeAttr("duty_cycle", strconv.FormatInt(d.Nanoseconds(), 10))
+}
+
+// SetPolarity sets the active level of the PWM output.
+func (p PWMPin) SetPolarity(pol Polarity) error {
+	switch pol {
+	case PolarityNormal:
+		return p.writeAttr("polarity", "normal")
+	case PolarityInversed:
+		return p.writeAttr("polarity", "inversed")
+	default:
+		return errors.New("invalid PWM polarity")
+	}
+}
+
+// Enable starts the PWM signal.
+func (p PWMPin) Enable() error {
+	return p.writeAttr("enable", "1")
+}
+
+// Disable stops the PWM signal.
+func (p PWMPin) Disable() error {
+	return p.writeAttr("enable", "0")
+}
+
+// SetFrequency sets the PWM period to 1/hz.
+func (p PWMPin) SetFrequency(hz float64) error {
+	if hz <= 0 {
+		return errors.New("frequency must be positive")
+	}
+	return p.SetPeriod(time.Duration(float64(time.Second) / hz))
+}
+
+// SetDutyPercent sets the duty cycle to percent of the channel's current period.
+func (p PWMPin) SetDutyPercent(percent float64) error {
+	if percent < 0 || percent > 100 {
+		return errors.New("duty percent must be between 0 and 100")
+	}
+	period, err := p.readPeriod()
+	if err != nil {
+		return err
+	}
+	return p.SetDutyCycle(time.Duration(float64(period) * percent / 100))
+}
+
+func (p PWMPin) readPeriod() (time.Duration, error) {
+	raw, err := os.ReadFile(p.path("period"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read pwmchip%d channel %d period: %v", p.Chip, p.Channel, err)
+	}
+	ns, err := strconv.ParseInt(strings.TrimSpace(string(raw)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ns), nil
+}
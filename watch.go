@@ -0,0 +1,304 @@
+package gpio
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Event describes a single edge detected on a watched Pin.
+type Event struct {
+	Pin       uint
+	Value     Value
+	Edge      Edge
+	Timestamp time.Time
+}
+
+// WatchOption configures a Pin.Watch subscription.
+type WatchOption func(*watchConfig)
+
+type watchConfig struct {
+	debounce time.Duration
+}
+
+// Debounce suppresses events occurring within d of the previous event reported for the pin.
+func Debounce(d time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		c.debounce = d
+	}
+}
+
+type subscriber struct {
+	pin      Pin
+	edge     Edge
+	events   chan Event
+	debounce time.Duration
+	lastTime time.Time
+	lastVal  Value
+	chardev  bool
+}
+
+var (
+	epollMu   sync.Mutex
+	epollFd   = -1
+	epollSubs = map[int32]*subscriber{}
+	epollStop chan struct{}
+)
+
+// Watch subscribes to edge events on an input pin, returning a channel that delivers
+// them. The shared epoll(7) loop backing every subscription is started lazily on the
+// first call and torn down once the last subscriber calls Unwatch.
+func (p Pin) Watch(edge Edge, opts ...WatchOption) (<-chan Event, error) {
+	if p.direction != inDirection {
+		return nil, fmt.Errorf("pin %d is not configured for input", p.Number)
+	}
+
+	cfg := watchConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if _, ok := p.backend.(gpiodBackend); ok {
+		return p.watchChardev(edge, cfg.debounce)
+	}
+
+	// Some SoCs/pins expose a value file but not an edge attribute; prefer kernel edge
+	// detection and only fall back to software polling when it is unavailable.
+	if !hasEdgeSupport(p) {
+		return p.watchByPolling(edge, cfg.debounce)
+	}
+	if err := setEdgeTrigger(p, edge); err != nil {
+		return nil, fmt.Errorf("failed to set edge trigger on pin %d: %v", p.Number, err)
+	}
+
+	// Seed the last-seen value from an explicit read before registering with epoll: the
+	// sysfs value file reports its fd as PRI-readable on the very first epoll_wait after
+	// registration regardless of whether a real transition occurred, so without a seed
+	// every subscription on a pin that is currently Active would emit a bogus EdgeRising.
+	initial, err := readPin(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read initial value of pin %d: %v", p.Number, err)
+	}
+
+	sub := &subscriber{
+		pin:      p,
+		edge:     edge,
+		events:   make(chan Event, 1),
+		debounce: cfg.debounce,
+		lastVal:  Value(initial),
+	}
+
+	if err := registerSubscriber(int32(p.f.Fd()), sub, unix.EPOLLPRI|unix.EPOLLERR); err != nil {
+		return nil, err
+	}
+
+	return sub.events, nil
+}
+
+// watchChardev subscribes to edge events on a chardev-backed Pin by reconfiguring its
+// already-requested line for edge detection and registering it with the shared epoll
+// loop for EPOLLIN, under which it delivers GPIO_V2_LINE_EVENT records directly.
+func (p Pin) watchChardev(edge Edge, debounce time.Duration) (<-chan Event, error) {
+	if err := setLineEdgeDetection(p, edge); err != nil {
+		return nil, fmt.Errorf("failed to enable edge detection on pin %d: %v", p.Number, err)
+	}
+
+	sub := &subscriber{
+		pin:      p,
+		edge:     edge,
+		events:   make(chan Event, 1),
+		debounce: debounce,
+		chardev:  true,
+	}
+
+	if err := registerSubscriber(int32(p.f.Fd()), sub, unix.EPOLLIN|unix.EPOLLERR); err != nil {
+		return nil, err
+	}
+
+	return sub.events, nil
+}
+
+func registerSubscriber(fd int32, sub *subscriber, epollEvents uint32) error {
+	epollMu.Lock()
+	defer epollMu.Unlock()
+
+	if err := startEpollLoopLocked(); err != nil {
+		return err
+	}
+
+	ev := unix.EpollEvent{Events: epollEvents, Fd: fd}
+	if err := unix.EpollCtl(epollFd, unix.EPOLL_CTL_ADD, int(fd), &ev); err != nil {
+		return fmt.Errorf("failed to register pin %d with epoll: %v", sub.pin.Number, err)
+	}
+	epollSubs[fd] = sub
+	return nil
+}
+
+// pollFallbackInterval is used by Watch when a pin has no kernel edge support.
+const pollFallbackInterval = 20 * time.Millisecond
+
+var (
+	pollMu   sync.Mutex
+	pollSubs = map[uint]func(){}
+)
+
+func hasEdgeSupport(p Pin) bool {
+	return unix.Access(fmt.Sprintf("/sys/class/gpio/gpio%d/edge", p.Number), unix.W_OK) == nil
+}
+
+// watchByPolling backs Watch with PollEdge for pins that cannot report edges through the
+// kernel, re-applying debounce here since PollEdge itself has no notion of it.
+func (p Pin) watchByPolling(edge Edge, debounce time.Duration) (<-chan Event, error) {
+	raw, cancel, err := PollEdge(p, edge, pollFallbackInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	pollMu.Lock()
+	pollSubs[p.Number] = cancel
+	pollMu.Unlock()
+
+	if debounce <= 0 {
+		return raw, nil
+	}
+
+	filtered := make(chan Event, 1)
+	go func() {
+		defer close(filtered)
+		var last time.Time
+		for ev := range raw {
+			if !last.IsZero() && ev.Timestamp.Sub(last) < debounce {
+				continue
+			}
+			last = ev.Timestamp
+			select {
+			case filtered <- ev:
+			default:
+			}
+		}
+	}()
+
+	return filtered, nil
+}
+
+// Unwatch cancels a previous Watch subscription for the pin and closes its event channel.
+func (p Pin) Unwatch() error {
+	pollMu.Lock()
+	if cancel, ok := pollSubs[p.Number]; ok {
+		delete(pollSubs, p.Number)
+		pollMu.Unlock()
+		cancel()
+		return nil
+	}
+	pollMu.Unlock()
+
+	epollMu.Lock()
+	defer epollMu.Unlock()
+
+	fd := int32(p.f.Fd())
+	sub, ok := epollSubs[fd]
+	if !ok {
+		return fmt.Errorf("pin %d has no active watch", p.Number)
+	}
+	if err := unix.EpollCtl(epollFd, unix.EPOLL_CTL_DEL, int(fd), nil); err != nil {
+		return fmt.Errorf("failed to unregister pin %d from epoll: %v", p.Number, err)
+	}
+	delete(epollSubs, fd)
+	close(sub.events)
+
+	if len(epollSubs) == 0 {
+		close(epollStop)
+		unix.Close(epollFd)
+		epollFd = -1
+	}
+	return nil
+}
+
+func startEpollLoopLocked() error {
+	if epollFd != -1 {
+		return nil
+	}
+	fd, err := unix.EpollCreate1(0)
+	if err != nil {
+		return fmt.Errorf("failed to create epoll instance: %v", err)
+	}
+	epollFd = fd
+	epollStop = make(chan struct{})
+	go epollLoop(fd, epollStop)
+	return nil
+}
+
+// epollLoop is the single goroutine shared by every Watch subscription. On the sysfs
+// backend it reacts to EPOLLPRI on the gpio value file; a chardev-backed Pin instead
+// delivers GPIO_V2_LINE_EVENT records directly on its line fd becoming readable.
+func epollLoop(fd int, stop chan struct{}) {
+	events := make([]unix.EpollEvent, 16)
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		n, err := unix.EpollWait(fd, events, 100)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return
+		}
+
+		now := time.Now()
+		epollMu.Lock()
+		for i := 0; i < n; i++ {
+			sub, ok := epollSubs[events[i].Fd]
+			if !ok {
+				continue
+			}
+
+			var out Event
+			if sub.chardev {
+				ev, err := readLineEvent(sub.pin)
+				if err != nil {
+					continue
+				}
+				out = ev
+			} else {
+				val, err := readPin(sub.pin)
+				if err != nil {
+					continue
+				}
+				detected := edgeFor(sub.lastVal, Value(val))
+				sub.lastVal = Value(val)
+				if detected == EdgeNone || (sub.edge != EdgeBoth && detected != sub.edge) {
+					continue
+				}
+				out = Event{Pin: sub.pin.Number, Value: Value(val), Edge: detected, Timestamp: now}
+			}
+
+			if sub.debounce > 0 && !sub.lastTime.IsZero() && now.Sub(sub.lastTime) < sub.debounce {
+				continue
+			}
+			sub.lastTime = now
+
+			select {
+			case sub.events <- out:
+			default:
+			}
+		}
+		epollMu.Unlock()
+	}
+}
+
+func edgeFor(previous, current Value) Edge {
+	switch {
+	case current == Active && previous == Inactive:
+		return EdgeRising
+	case current == Inactive && previous == Active:
+		return EdgeFalling
+	default:
+		return EdgeNone
+	}
+}